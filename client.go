@@ -5,16 +5,16 @@ import (
 	core "github.com/phpdragon/go-eurake-client/core"
 	log "github.com/phpdragon/go-eurake-client/log"
 	netUtil "github.com/phpdragon/go-eurake-client/netutil"
-	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
-	"unsafe"
 )
 
 const (
@@ -22,17 +22,11 @@ const (
 	//
 	httpPrefix  = "http://"
 	httpsPrefix = "https://"
-	//
-	httpKey  = 0
-	httpsKey = 1
 )
 
 type Client struct {
 	Running bool
 
-	//自增器
-	autoInc *atomic.Int64
-
 	// for monitor system signal
 	signalChan chan os.Signal
 
@@ -58,14 +52,32 @@ type Client struct {
 	//		value: InstanceConfig
 	activeInstanceMap map[string]map[int]*core.Instance
 
-	// instance real url map
-	// key: appId
-	// value:
-	//		key:  int(http:0, https:1)
-	//		value:
-	//			key:  int(0...n)
-	//			value: real url
-	activeServiceIpPortMap map[string]map[int]map[int]string
+	// appsHashcode is the last computed "STATUS_count_" hashcode (instance
+	// counts per status) across the full registry. It's compared against the server's apps__hashcode
+	// after every delta is applied to detect cache drift.
+	appsHashcode string
+
+	// serverList tracks the shuffled/quarantined eureka server urls used by
+	// pickEurekaServerApi.
+	serverList *serverList
+
+	// appSubscribers holds the live Subscribe(appId) channels, keyed by the
+	// same uppercased appId used by activeInstanceMap.
+	appSubscribers map[string][]chan []*core.Instance
+
+	// allSubscribers holds the live SubscribeAll channels.
+	allSubscribers []chan RegistryEvent
+
+	// governor is the embedded admin HTTP server, started by Run() when
+	// ClientConfig.GovernorAddr is set.
+	governor *http.Server
+
+	lastHeartbeatAt time.Time
+	lastFetchAt     time.Time
+
+	// registrySyncCancel stops the registry-sync driver started by startSync,
+	// if one was started.
+	registrySyncCancel func()
 }
 
 func NewClient(config *Config) *Client {
@@ -75,14 +87,17 @@ func NewClient(config *Config) *Client {
 func NewClientWithLog(config *Config, zapLog *zap.Logger) *Client {
 	instanceConfig, _ := NewInstance(config)
 
+	if config.LoadBalancer == nil {
+		config.LoadBalancer = NewRoundRobin()
+	}
+
 	client := &Client{
-		//自增器
-		autoInc:    atomic.NewInt64(0),
 		logger:     log.NewLogAgent(zapLog),
 		signalChan: make(chan os.Signal),
 		//
-		config:   config,
-		instance: instanceConfig,
+		config:     config,
+		instance:   instanceConfig,
+		serverList: newServerList(),
 	}
 
 	return client
@@ -100,10 +115,22 @@ func (client *Client) Run() {
 	// and update to t.registryAppMap
 	go client.refreshRegistry()
 
+	// (if GovernorAddr is set), expose the admin/status HTTP endpoints
+	client.startGovernor()
+
+	// (if SyncTargets is non-empty), mirror the registry into other backends
+	client.startSync()
+
 	client.registerWithEureka()
 }
 
 func (client *Client) Shutdown() {
+	client.stopGovernor()
+
+	if client.registrySyncCancel != nil {
+		client.registrySyncCancel()
+	}
+
 	//client在shutdown情况下，是否显示从注册中心注销
 	if !client.Running || !client.config.ClientConfig.ShouldUnregisterOnShutdown {
 		return
@@ -119,6 +146,9 @@ func (client *Client) Shutdown() {
 	err = api.DeRegisterInstance(client.instance.App, client.instance.InstanceId)
 	if err != nil {
 		client.logger.Error(fmt.Sprintf("Failed to de-register %s, err=%s", client.instance.InstanceId, err.Error()))
+		if core.IsServerError(err) {
+			client.quarantineServerUrl(api.GetBaseUrl())
+		}
 		return
 	}
 
@@ -130,14 +160,28 @@ func (client *Client) Shutdown() {
 }
 
 func (client *Client) GetApplications() map[string]*core.Application {
-	return client.registryAppMap
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+
+	apps := make(map[string]*core.Application, len(client.registryAppMap))
+	for appId, app := range client.registryAppMap {
+		apps[appId] = app
+	}
+	return apps
 }
 
 func (client *Client) GetInstances() map[string]map[int]*core.Instance {
-	return client.activeInstanceMap
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+
+	instances := make(map[string]map[int]*core.Instance, len(client.activeInstanceMap))
+	for appId, instanceMap := range client.activeInstanceMap {
+		instances[appId] = instanceMap
+	}
+	return instances
 }
 
-//获取下一个容器
+// 获取下一个容器
 func (client *Client) GetNextServerFromEureka(appId string) (*core.Instance, error) {
 	instanceMap, err := client.getActiveInstancesByAppId(appId)
 	if nil != err {
@@ -149,13 +193,11 @@ func (client *Client) GetNextServerFromEureka(appId string) (*core.Instance, err
 		return &core.Instance{}, fmt.Errorf("This %s instances not exist!", appId)
 	}
 
-	index := client.getRandIndex(len(instanceMap))
-	return instanceMap[index], nil
-}
-
-func (client *Client) getRandIndex(total int) int {
-	var index64 = client.autoInc.Inc() % int64(total)
-	return *(*int)(unsafe.Pointer(&index64))
+	instance, err := client.config.LoadBalancer.Choose(appId, toInstanceSlice(instanceMap))
+	if err != nil {
+		return &core.Instance{}, err
+	}
+	return instance, nil
 }
 
 func (client *Client) GetRealHttpUrl(httpUrl string) (string, error) {
@@ -165,27 +207,30 @@ func (client *Client) GetRealHttpUrl(httpUrl string) (string, error) {
 	appName := urls[0]
 
 	//是否https
-	mapKey := httpKey
-	if strings.HasPrefix(httpUrl, httpsPrefix) {
-		mapKey = httpsKey
-	}
+	useSecurePort := strings.HasPrefix(httpUrl, httpsPrefix)
 
-	ipPortMap, err := client.getActiveServiceIpPortByAppId(appName)
-	if nil != err || 0 == len(ipPortMap) {
+	instanceMap, err := client.getActiveInstancesByAppId(appName)
+	if nil != err || 0 == len(instanceMap) {
 		//TODO：文案
 		return "", fmt.Errorf("This %s instances not exist!", appName)
 	}
 
-	//取http还是https的ip:port
-	realIpPorts := ipPortMap[mapKey]
-	if nil == realIpPorts || 0 == len(realIpPorts) {
+	candidates := filterByPortEnabled(toInstanceSlice(instanceMap), useSecurePort)
+	if 0 == len(candidates) {
 		//TODO：文案
 		return "", fmt.Errorf("This %s instances not exist!", appName)
 	}
 
-	//随机取一个目标ip:port
-	index := client.getRandIndex(len(realIpPorts))
-	realIpPort := realIpPorts[index]
+	instance, err := client.config.LoadBalancer.Choose(appName, candidates)
+	if err != nil {
+		return "", err
+	}
+
+	port := instance.Port.Port
+	if useSecurePort {
+		port = instance.SecurePort.Port
+	}
+	realIpPort := fmt.Sprintf("%s:%d", instance.IpAddr, port)
 
 	return strings.Replace(httpUrl, appName, realIpPort, -1), nil
 }
@@ -205,21 +250,6 @@ func (client *Client) getActiveInstancesByAppId(appId string) (map[int]*core.Ins
 	return client.activeInstanceMap[id], nil
 }
 
-func (client *Client) getActiveServiceIpPortByAppId(appId string) (map[int]map[int]string, error) {
-	id := strings.ToUpper(appId)
-	cache := client.activeServiceIpPortMap[id]
-	if nil != cache {
-		return client.activeServiceIpPortMap[id], nil
-	}
-
-	err := client.doRefreshByAppId(appId)
-	if nil != err {
-		return nil, err
-	}
-
-	return client.activeServiceIpPortMap[id], nil
-}
-
 func (client *Client) doRefreshByAppId(appId string) error {
 	api, err := client.Api()
 	if err != nil {
@@ -228,17 +258,19 @@ func (client *Client) doRefreshByAppId(appId string) error {
 
 	application, errr := api.QueryAllInstanceByAppId(appId)
 	if errr != nil {
+		if core.IsServerError(errr) {
+			client.quarantineServerUrl(api.GetBaseUrl())
+		}
 		return errr
 	}
 
-	instances, urls := getActiveInstancesAndIpPorts(client.config.ClientConfig.FilterOnlyUpInstances, application.Instances)
+	instances := getActiveInstances(client.config.ClientConfig.FilterOnlyUpInstances, application.Instances)
 
 	client.mutex.Lock()
 	defer client.mutex.Unlock()
 
 	client.registryAppMap[appId] = application
 	client.activeInstanceMap[appId] = instances
-	client.activeServiceIpPortMap[appId] = urls
 
 	return nil
 }
@@ -248,13 +280,28 @@ func (client *Client) refreshRegistry() {
 		return
 	}
 
+	if err := client.fetchRegistry(); err != nil {
+		client.logger.Error(fmt.Sprintf("Initial fetchRegistry failed, err=%s", err.Error()))
+	}
+
+	if client.config.ClientConfig.DisableDelta {
+		for {
+			time.Sleep(time.Second * time.Duration(client.config.ClientConfig.getRegistryFetchIntervalSeconds()))
+			_ = client.fetchRegistry()
+		}
+	}
+
 	for {
-		_ = client.fetchRegistry()
-		time.Sleep(time.Second * time.Duration(client.config.ClientConfig.getRegistryFetchIntervalSeconds()))
+		time.Sleep(time.Second * time.Duration(client.config.ClientConfig.getDeltaFetchIntervalSeconds()))
+
+		if err := client.fetchDelta(); err != nil {
+			client.logger.Warn(fmt.Sprintf("fetchDelta failed, falling back to full fetchRegistry, err=%s", err.Error()))
+			_ = client.fetchRegistry()
+		}
 	}
 }
 
-//刷新服务列表
+// 刷新服务列表
 func (client *Client) fetchRegistry() error {
 	client.logger.Info("Fetch registry info")
 
@@ -267,30 +314,193 @@ func (client *Client) fetchRegistry() error {
 	apps, err := api.QueryAllInstances()
 	if err != nil {
 		client.logger.Error(fmt.Sprintf("Failed to QueryAllInstances, err=%s", err.Error()))
+		if core.IsServerError(err) {
+			client.quarantineServerUrl(api.GetBaseUrl())
+		}
 		return err
 	}
 
 	registryApps := make(map[string]*core.Application)
 	activeInstances := make(map[string]map[int]*core.Instance)
-	activeServiceUrls := make(map[string]map[int]map[int]string)
 
 	for _, app := range apps.Applications {
-		instances, urls := getActiveInstancesAndIpPorts(client.config.ClientConfig.FilterOnlyUpInstances, app.Instances)
+		instances := getActiveInstances(client.config.ClientConfig.FilterOnlyUpInstances, app.Instances)
 		registryApps[app.Name] = &app
 		activeInstances[app.Name] = instances
-		activeServiceUrls[app.Name] = urls
 	}
 
 	client.mutex.Lock()
 	defer client.mutex.Unlock()
 
+	previousInstances := client.activeInstanceMap
+
 	client.registryAppMap = registryApps
 	client.activeInstanceMap = activeInstances
-	client.activeServiceIpPortMap = activeServiceUrls
+	client.appsHashcode = computeAppsHashcode(registryApps)
+	client.lastFetchAt = time.Now()
+
+	for appId := range mergeAppIdKeys(previousInstances, activeInstances) {
+		client.notifySubscribersLocked(appId, toInstanceSlice(previousInstances[appId]), toInstanceSlice(activeInstances[appId]))
+	}
 
 	return nil
 }
 
+// mergeAppIdKeys returns the union of both maps' keys.
+func mergeAppIdKeys(a, b map[string]map[int]*core.Instance) map[string]struct{} {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for appId := range a {
+		keys[appId] = struct{}{}
+	}
+	for appId := range b {
+		keys[appId] = struct{}{}
+	}
+	return keys
+}
+
+// toInstanceSlice flattens an activeInstanceMap entry into a plain slice.
+func toInstanceSlice(instances map[int]*core.Instance) []*core.Instance {
+	slice := make([]*core.Instance, 0, len(instances))
+	for _, instance := range instances {
+		slice = append(slice, instance)
+	}
+	return slice
+}
+
+// fetchDelta pulls only the ADDED/MODIFIED/DELETED instance actions since the
+// last full/delta fetch and mutates the caches in place. If the resulting
+// apps__hashcode doesn't match what the server reports, the caller should
+// fall back to a full fetchRegistry to reconcile.
+func (client *Client) fetchDelta() error {
+	api, err := client.Api()
+	if err != nil {
+		return err
+	}
+
+	delta, err := api.QueryDelta()
+	if err != nil {
+		if core.IsServerError(err) {
+			client.quarantineServerUrl(api.GetBaseUrl())
+		}
+		return err
+	}
+
+	added, modified, deleted := 0, 0, 0
+
+	client.mutex.Lock()
+	for _, app := range delta.Applications {
+		for _, instance := range app.Instances {
+			switch instance.ActionType {
+			case core.ActionAdded, core.ActionModified:
+				client.applyInstanceLocked(app.Name, instance.Instance)
+				if instance.ActionType == core.ActionAdded {
+					added++
+				} else {
+					modified++
+				}
+			case core.ActionDeleted:
+				client.removeInstanceLocked(app.Name, instance.Instance.InstanceId)
+				deleted++
+			}
+		}
+	}
+
+	client.appsHashcode = computeAppsHashcode(client.registryAppMap)
+	client.lastFetchAt = time.Now()
+	mismatch := client.appsHashcode != delta.AppsHashcode
+	client.mutex.Unlock()
+
+	client.logger.Debug(fmt.Sprintf("Applied delta: added=%d, modified=%d, deleted=%d", added, modified, deleted))
+
+	if mismatch {
+		return fmt.Errorf("local apps hashcode [%s] diverged from server hashcode [%s]", client.appsHashcode, delta.AppsHashcode)
+	}
+
+	return nil
+}
+
+// applyInstanceLocked upserts instance into appId's application/instance
+// caches. Like fetchRegistry, it always swaps in a brand-new *core.Application
+// rather than mutating the existing one in place, so a reader that copied the
+// old pointer out from under a short RLock (e.g. the governor's /apps
+// handlers) never observes a half-written slice. Callers must hold
+// client.mutex.
+func (client *Client) applyInstanceLocked(appId string, instance core.Instance) {
+	existing := client.registryAppMap[appId]
+
+	replaced := false
+	var instances []core.Instance
+	if existing != nil {
+		instances = make([]core.Instance, len(existing.Instances))
+		copy(instances, existing.Instances)
+		for i := range instances {
+			if instances[i].InstanceId == instance.InstanceId {
+				instances[i] = instance
+				replaced = true
+				break
+			}
+		}
+	}
+	if !replaced {
+		instances = append(instances, instance)
+	}
+
+	client.registryAppMap[appId] = &core.Application{Name: appId, Instances: instances}
+
+	previous := toInstanceSlice(client.activeInstanceMap[appId])
+	activeInstances := getActiveInstances(client.config.ClientConfig.FilterOnlyUpInstances, instances)
+	client.activeInstanceMap[appId] = activeInstances
+	client.notifySubscribersLocked(appId, previous, toInstanceSlice(activeInstances))
+}
+
+// removeInstanceLocked drops instanceId from appId's application/instance
+// caches, swapping in a brand-new *core.Application (see applyInstanceLocked
+// for why). Callers must hold client.mutex.
+func (client *Client) removeInstanceLocked(appId string, instanceId string) {
+	existing, ok := client.registryAppMap[appId]
+	if !ok {
+		return
+	}
+
+	instances := make([]core.Instance, 0, len(existing.Instances))
+	for _, instance := range existing.Instances {
+		if instance.InstanceId != instanceId {
+			instances = append(instances, instance)
+		}
+	}
+
+	client.registryAppMap[appId] = &core.Application{Name: appId, Instances: instances}
+
+	previous := toInstanceSlice(client.activeInstanceMap[appId])
+	activeInstances := getActiveInstances(client.config.ClientConfig.FilterOnlyUpInstances, instances)
+	client.activeInstanceMap[appId] = activeInstances
+	client.notifySubscribersLocked(appId, previous, toInstanceSlice(activeInstances))
+}
+
+// computeAppsHashcode reproduces eureka's "STATUS_count_" hashcode so the
+// locally-applied delta can be checked against apps__hashcode.
+func computeAppsHashcode(apps map[string]*core.Application) string {
+	counts := make(map[string]int)
+	for _, app := range apps {
+		for _, instance := range app.Instances {
+			counts[instance.Status]++
+		}
+	}
+
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var hashcode strings.Builder
+	for _, status := range statuses {
+		hashcode.WriteString(fmt.Sprintf("%s_%d_", status, counts[status]))
+	}
+
+	return hashcode.String()
+}
+
 // register instance (default current status is STARTING)
 // and update instance status to UP
 func (client *Client) registerWithEureka() {
@@ -314,6 +524,9 @@ func (client *Client) registerWithEureka() {
 		err = api.RegisterInstance(client.instance.App, client.instance)
 		if err != nil {
 			client.logger.Error(fmt.Sprintf("ClientConfig register failed, err=%s", err.Error()))
+			if core.IsServerError(err) {
+				client.quarantineServerUrl(api.GetBaseUrl())
+			}
 			time.Sleep(time.Second * defaultSleepIntervals)
 			continue
 		}
@@ -342,7 +555,7 @@ func (client *Client) registerWithEureka() {
 	go client.heartbeat()
 }
 
-//判断http服务是否已经启动
+// 判断http服务是否已经启动
 func (client *Client) serverIsStarted() bool {
 	port := client.instance.Port.Port
 	if "true" == client.instance.SecurePort.Enabled {
@@ -389,6 +602,9 @@ func (client *Client) updateInstanceStatus() (bool, error) {
 	// then break loop
 	err = api.UpdateInstanceStatus(client.instance.App, client.instance.InstanceId, core.STATUS_UP)
 	if err != nil {
+		if core.IsServerError(err) {
+			client.quarantineServerUrl(api.GetBaseUrl())
+		}
 		client.logger.Error(fmt.Sprintf("ClientConfig UP failed, err=%s", err.Error()))
 		return false, nil
 	}
@@ -407,12 +623,6 @@ func (client *Client) Api() (*core.EurekaServerApi, error) {
 	return api, nil
 }
 
-//TODO:
-// rand to pick service url and new EurekaServerApi instance
-func (client *Client) pickEurekaServerApi() (*core.EurekaServerApi, error) {
-	return core.NewEurekaServerApi(client.config.ServiceURL.DefaultZone), nil
-}
-
 // 发送心跳
 // eureka client heartbeat
 func (client *Client) heartbeat() {
@@ -426,22 +636,26 @@ func (client *Client) heartbeat() {
 		err = api.SendHeartbeat(client.instance.App, client.instance.InstanceId)
 		if err != nil {
 			client.logger.Error(fmt.Sprintf("Failed to send heartbeat, err=%s", err.Error()))
+			if core.IsServerError(err) {
+				client.quarantineServerUrl(api.GetBaseUrl())
+			}
 			time.Sleep(time.Second * defaultSleepIntervals)
 			continue
 		}
 
 		client.logger.Debug(fmt.Sprintf("Heartbeat app=%s, instanceId=%s", client.instance.App, client.instance.InstanceId))
+
+		client.mutex.Lock()
+		client.lastHeartbeatAt = time.Now()
+		client.mutex.Unlock()
+
 		time.Sleep(time.Duration(client.config.InstanceConfig.LeaseInfo.RenewalIntervalInSecs) * time.Second)
 	}
 }
 
-//获取有效的实例和链接
-func getActiveInstancesAndIpPorts(filterOnlyUpInstances bool, instances []core.Instance) (map[int]*core.Instance, map[int]map[int]string) {
+// 获取有效的实例
+func getActiveInstances(filterOnlyUpInstances bool, instances []core.Instance) map[int]*core.Instance {
 	instancesX := make(map[int]*core.Instance)
-	//
-	urls := make(map[int]map[int]string)
-	httpUrls := make(map[int]string)
-	httpsUrls := make(map[int]string)
 	instanceTotal := len(instances)
 	for i := 0; i < instanceTotal; i++ {
 		instance := instances[i]
@@ -451,23 +665,32 @@ func getActiveInstancesAndIpPorts(filterOnlyUpInstances bool, instances []core.I
 		}
 
 		instancesX[i] = &instance
+	}
 
-		if "true" == instance.Port.Enabled {
-			httpUrls[i] = fmt.Sprintf("%s:%d", instance.IpAddr, instance.Port.Port)
+	return instancesX
+}
+
+// filterByPortEnabled returns the instances that have the relevant port
+// (https when useSecurePort, http otherwise) enabled, for callers that need
+// to build a URL out of the chosen instance.
+func filterByPortEnabled(instances []*core.Instance, useSecurePort bool) []*core.Instance {
+	filtered := make([]*core.Instance, 0, len(instances))
+	for _, instance := range instances {
+		enabled := instance.Port.Enabled
+		if useSecurePort {
+			enabled = instance.SecurePort.Enabled
 		}
-		if "false" == instance.SecurePort.Enabled {
-			httpsUrls[i] = fmt.Sprintf("%s:%d", instance.IpAddr, instance.SecurePort.Port)
+		if "true" == enabled {
+			filtered = append(filtered, instance)
 		}
 	}
-
-	urls[httpKey] = httpUrls
-	urls[httpsKey] = httpsUrls
-	return instancesX, urls
+	return filtered
 }
 
 // for graceful kill. Here handle SIGTERM signal to do sth
 // e.g: kill -TERM $pid
-//      or "ctrl + c" to exit
+//
+//	or "ctrl + c" to exit
 func (client *Client) handleSignal() {
 	if client.signalChan == nil {
 		client.signalChan = make(chan os.Signal)
@@ -494,4 +717,4 @@ func (client *Client) handleSignal() {
 			os.Exit(0)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,97 @@
+package eureka
+
+import (
+	core "github.com/phpdragon/go-eurake-client/core"
+	log "github.com/phpdragon/go-eurake-client/log"
+	"testing"
+)
+
+func newTestClient() *Client {
+	return &Client{
+		logger:            log.NewLogAgent(nil),
+		config:            &Config{},
+		registryAppMap:    make(map[string]*core.Application),
+		activeInstanceMap: make(map[string]map[int]*core.Instance),
+	}
+}
+
+func TestApplyInstanceLocked_NotifiesSubscribersOnChange(t *testing.T) {
+	client := newTestClient()
+	ch, cancel := client.Subscribe("app1")
+	defer cancel()
+
+	client.mutex.Lock()
+	client.applyInstanceLocked("APP1", core.Instance{InstanceId: "i1", Status: core.STATUS_UP})
+	client.mutex.Unlock()
+
+	select {
+	case instances := <-ch:
+		if len(instances) != 1 || instances[0].InstanceId != "i1" {
+			t.Fatalf("unexpected snapshot: %+v", instances)
+		}
+	default:
+		t.Fatal("expected a snapshot after applyInstanceLocked added an instance")
+	}
+}
+
+func TestApplyInstanceLocked_ReplacesExistingInstanceByIdWithoutAliasingOldApplication(t *testing.T) {
+	client := newTestClient()
+
+	client.mutex.Lock()
+	client.applyInstanceLocked("APP1", core.Instance{InstanceId: "i1", Status: core.STATUS_UP})
+	oldApp := client.registryAppMap["APP1"]
+	client.applyInstanceLocked("APP1", core.Instance{InstanceId: "i1", Status: core.STATUS_DOWN})
+	client.mutex.Unlock()
+
+	if oldApp.Instances[0].Status != core.STATUS_UP {
+		t.Fatalf("previously captured *core.Application was mutated in place, status=%s", oldApp.Instances[0].Status)
+	}
+
+	newApp := client.registryAppMap["APP1"]
+	if len(newApp.Instances) != 1 || newApp.Instances[0].Status != core.STATUS_DOWN {
+		t.Fatalf("unexpected current application state: %+v", newApp.Instances)
+	}
+}
+
+func TestRemoveInstanceLocked_NotifiesSubscribersAndDropsInstance(t *testing.T) {
+	client := newTestClient()
+
+	client.mutex.Lock()
+	client.applyInstanceLocked("APP1", core.Instance{InstanceId: "i1", Status: core.STATUS_UP})
+	client.applyInstanceLocked("APP1", core.Instance{InstanceId: "i2", Status: core.STATUS_UP})
+	client.mutex.Unlock()
+
+	ch, cancel := client.Subscribe("app1")
+	defer cancel()
+
+	client.mutex.Lock()
+	client.removeInstanceLocked("APP1", "i1")
+	client.mutex.Unlock()
+
+	select {
+	case instances := <-ch:
+		if len(instances) != 1 || instances[0].InstanceId != "i2" {
+			t.Fatalf("unexpected snapshot after removal: %+v", instances)
+		}
+	default:
+		t.Fatal("expected a snapshot after removeInstanceLocked dropped an instance")
+	}
+}
+
+func TestInstanceSetsEqual(t *testing.T) {
+	a := []*core.Instance{{InstanceId: "i1", Status: core.STATUS_UP}, {InstanceId: "i2", Status: core.STATUS_UP}}
+	b := []*core.Instance{{InstanceId: "i2", Status: core.STATUS_UP}, {InstanceId: "i1", Status: core.STATUS_UP}}
+	if !instanceSetsEqual(a, b) {
+		t.Fatal("expected order-independent sets with matching status to be equal")
+	}
+
+	c := []*core.Instance{{InstanceId: "i1", Status: core.STATUS_DOWN}, {InstanceId: "i2", Status: core.STATUS_UP}}
+	if instanceSetsEqual(a, c) {
+		t.Fatal("expected differing status to make sets unequal")
+	}
+
+	d := []*core.Instance{{InstanceId: "i1", Status: core.STATUS_UP}}
+	if instanceSetsEqual(a, d) {
+		t.Fatal("expected differing membership to make sets unequal")
+	}
+}
@@ -0,0 +1,115 @@
+package eureka
+
+import (
+	registrysync "github.com/phpdragon/go-eurake-client/sync"
+)
+
+// Config is the root configuration object passed to NewClient / NewClientWithLog.
+type Config struct {
+	ServiceURL     ServiceURL
+	ClientConfig   ClientConfig
+	InstanceConfig InstanceConfig
+
+	// LoadBalancer picks which instance GetNextServerFromEureka/GetRealHttpUrl
+	// returns for a given appId. Defaults to RoundRobin when nil.
+	LoadBalancer LoadBalancer
+}
+
+// ServiceURL describes where the eureka server(s) can be reached.
+type ServiceURL struct {
+	// DefaultZone is a comma-separated list of eureka server URLs, e.g.
+	// "http://server1:8761/eureka/,http://server2:8761/eureka/". Used only
+	// when Zones yields no URLs; it is not appended after the Zones URLs.
+	DefaultZone string
+
+	// Zones maps a zone name to its comma-separated list of eureka server
+	// URLs, mirroring the Java client's eureka.client.availability-zones.
+	Zones map[string]string
+
+	// PreferredZone, when set and present in Zones, has its URLs tried first.
+	PreferredZone string
+
+	// ServerListRefreshIntervalMs controls how long a URL stays quarantined
+	// after a transport/5xx failure before it's eligible again.
+	ServerListRefreshIntervalMs int
+}
+
+const defaultServerListRefreshIntervalMs = 5 * 60 * 1000
+
+func (s *ServiceURL) getServerListRefreshIntervalMs() int {
+	if s.ServerListRefreshIntervalMs <= 0 {
+		return defaultServerListRefreshIntervalMs
+	}
+	return s.ServerListRefreshIntervalMs
+}
+
+// ClientConfig controls how this client talks to the eureka server.
+type ClientConfig struct {
+	// RegisterWithEureka indicates whether this instance should register itself.
+	RegisterWithEureka bool
+
+	// FetchRegistry indicates whether this client should fetch the registry.
+	FetchRegistry bool
+
+	// FilterOnlyUpInstances, if true, drops non-UP instances from the local cache.
+	FilterOnlyUpInstances bool
+
+	// ShouldUnregisterOnShutdown controls whether Shutdown() de-registers the instance.
+	ShouldUnregisterOnShutdown bool
+
+	// RegistryFetchIntervalSeconds is how often the full registry is re-fetched
+	// when DisableDelta is true. With delta fetching on, a full fetch happens
+	// only at start and after a failed or mismatched delta. Defaults to defaultRegistryFetchIntervalSeconds when <= 0.
+	RegistryFetchIntervalSeconds int
+
+	// DisableDelta forces every refresh tick to do a full fetchRegistry instead
+	// of the lighter-weight delta fetch.
+	DisableDelta bool
+
+	// DeltaFetchIntervalSeconds is how often the delta endpoint is polled once
+	// DisableDelta is false. Defaults to defaultDeltaFetchIntervalSeconds when <= 0.
+	DeltaFetchIntervalSeconds int
+
+	// GovernorAddr, when non-empty (e.g. ":9999"), starts an embedded admin
+	// HTTP server exposing /status, /apps, /instance/status, /deregister and
+	// /debug/pprof/*.
+	GovernorAddr string
+
+	// SyncTargets, when non-empty, starts a sync.RegistrySync driver from
+	// Run() that mirrors the registry into these backends.
+	SyncTargets []registrysync.Syncer
+}
+
+const (
+	defaultRegistryFetchIntervalSeconds = 30
+	defaultDeltaFetchIntervalSeconds    = 30
+)
+
+func (c *ClientConfig) getRegistryFetchIntervalSeconds() int {
+	if c.RegistryFetchIntervalSeconds <= 0 {
+		return defaultRegistryFetchIntervalSeconds
+	}
+	return c.RegistryFetchIntervalSeconds
+}
+
+func (c *ClientConfig) getDeltaFetchIntervalSeconds() int {
+	if c.DeltaFetchIntervalSeconds <= 0 {
+		return defaultDeltaFetchIntervalSeconds
+	}
+	return c.DeltaFetchIntervalSeconds
+}
+
+// InstanceConfig describes the instance that will be registered to eureka.
+type InstanceConfig struct {
+	// InstanceEnabledOnInit, if true, marks the instance UP as soon as it registers
+	// instead of waiting for serverIsStarted() to report the web server is up.
+	InstanceEnabledOnInit bool
+
+	LeaseInfo LeaseInfo
+}
+
+// LeaseInfo mirrors the renewal/expiration settings eureka expects.
+type LeaseInfo struct {
+	RenewalIntervalInSecs int
+	DurationInSecs        int
+}
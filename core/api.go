@@ -0,0 +1,143 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// HttpStatusError is returned by doRequest when the eureka server responds
+// with a status code >= 300. StatusCode lets callers tell a transport
+// failure/5xx (which should quarantine the server url) apart from an
+// ordinary 4xx business response (which shouldn't).
+type HttpStatusError struct {
+	StatusCode int
+	Method     string
+	Path       string
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("eureka server returned status %d for %s %s", e.StatusCode, e.Method, e.Path)
+}
+
+// IsServerError reports whether err is a transport failure (anything that
+// isn't an *HttpStatusError) or a 5xx *HttpStatusError — the cases callers
+// should quarantine the eureka server url for. A 4xx HttpStatusError (bad
+// request, not found, ...) is a normal business response and reports false.
+func IsServerError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *HttpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	return true
+}
+
+// EurekaServerApi is a thin REST client bound to a single eureka server URL.
+type EurekaServerApi struct {
+	baseUrl    string
+	httpClient *http.Client
+}
+
+// GetBaseUrl returns the eureka server URL this api instance talks to.
+func (api *EurekaServerApi) GetBaseUrl() string {
+	return api.baseUrl
+}
+
+// NewEurekaServerApi builds an EurekaServerApi that talks to baseUrl.
+func NewEurekaServerApi(baseUrl string) *EurekaServerApi {
+	return &EurekaServerApi{
+		baseUrl: baseUrl,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (api *EurekaServerApi) doRequest(method, path string, body interface{}, out interface{}) error {
+	var reqBody *bytes.Buffer
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(data)
+	} else {
+		reqBody = bytes.NewBuffer(nil)
+	}
+
+	req, err := http.NewRequest(method, api.baseUrl+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := api.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return &HttpStatusError{StatusCode: resp.StatusCode, Method: method, Path: path}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// QueryAllInstances fetches the full registry.
+func (api *EurekaServerApi) QueryAllInstances() (*Applications, error) {
+	envelope := &applicationsEnvelope{}
+	if err := api.doRequest(http.MethodGet, "/apps", nil, envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.Applications, nil
+}
+
+// QueryAllInstanceByAppId fetches the instances registered under appId.
+func (api *EurekaServerApi) QueryAllInstanceByAppId(appId string) (*Application, error) {
+	envelope := &applicationEnvelope{}
+	if err := api.doRequest(http.MethodGet, "/apps/"+appId, nil, envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.Application, nil
+}
+
+// RegisterInstance registers instance under appId.
+func (api *EurekaServerApi) RegisterInstance(appId string, instance *Instance) error {
+	return api.doRequest(http.MethodPost, "/apps/"+appId, instance, nil)
+}
+
+// DeRegisterInstance removes instanceId from appId.
+func (api *EurekaServerApi) DeRegisterInstance(appId string, instanceId string) error {
+	return api.doRequest(http.MethodDelete, "/apps/"+appId+"/"+instanceId, nil, nil)
+}
+
+// UpdateInstanceStatus updates instanceId's status under appId.
+func (api *EurekaServerApi) UpdateInstanceStatus(appId string, instanceId string, status string) error {
+	return api.doRequest(http.MethodPut, "/apps/"+appId+"/"+instanceId+"/status?value="+status, nil, nil)
+}
+
+// SendHeartbeat renews instanceId's lease under appId.
+func (api *EurekaServerApi) SendHeartbeat(appId string, instanceId string) error {
+	return api.doRequest(http.MethodPut, "/apps/"+appId+"/"+instanceId, nil, nil)
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// realApplicationsPayload is shaped like an actual eureka GET /apps response:
+// everything nested under a top-level "applications" object.
+const realApplicationsPayload = `{
+	"applications": {
+		"application": [
+			{
+				"name": "MY-SERVICE",
+				"instance": [
+					{
+						"instanceId": "i-1",
+						"app": "MY-SERVICE",
+						"ipAddr": "10.0.0.1",
+						"status": "UP",
+						"port": {"$": 8080, "@enabled": "true"},
+						"securePort": {"$": 8443, "@enabled": "false"},
+						"metadata": {"zone": "a"}
+					}
+				]
+			}
+		],
+		"apps__hashcode": "UP_1_"
+	}
+}`
+
+// realApplicationPayload is shaped like an actual eureka GET /apps/{appId}
+// response: a single application nested under a top-level "application" object.
+const realApplicationPayload = `{
+	"application": {
+		"name": "MY-SERVICE",
+		"instance": [
+			{
+				"instanceId": "i-1",
+				"app": "MY-SERVICE",
+				"ipAddr": "10.0.0.1",
+				"status": "UP",
+				"port": {"$": 8080, "@enabled": "true"},
+				"securePort": {"$": 8443, "@enabled": "false"}
+			}
+		]
+	}
+}`
+
+// realDeltaPayload is shaped like an actual eureka GET /apps/delta response:
+// same "applications" wrapper as GET /apps.
+const realDeltaPayload = `{
+	"applications": {
+		"application": [
+			{
+				"name": "MY-SERVICE",
+				"instance": [
+					{
+						"instanceId": "i-1",
+						"app": "MY-SERVICE",
+						"ipAddr": "10.0.0.1",
+						"status": "UP",
+						"port": {"$": 8080, "@enabled": "true"},
+						"securePort": {"$": 8443, "@enabled": "false"},
+						"actionType": "ADDED"
+					}
+				]
+			}
+		],
+		"apps__hashcode": "UP_1_"
+	}
+}`
+
+func serveJson(t *testing.T, payload string) (*EurekaServerApi, func()) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(payload))
+	}))
+	return NewEurekaServerApi(server.URL), server.Close
+}
+
+func TestQueryAllInstances_UnwrapsRealEurekaEnvelope(t *testing.T) {
+	api, closeServer := serveJson(t, realApplicationsPayload)
+	defer closeServer()
+
+	apps, err := api.QueryAllInstances()
+	if err != nil {
+		t.Fatalf("QueryAllInstances returned err=%s", err)
+	}
+
+	if apps.AppsHashcode != "UP_1_" {
+		t.Fatalf("AppsHashcode = %q, want %q", apps.AppsHashcode, "UP_1_")
+	}
+	if len(apps.Applications) != 1 || apps.Applications[0].Name != "MY-SERVICE" {
+		t.Fatalf("unexpected Applications: %+v", apps.Applications)
+	}
+	if len(apps.Applications[0].Instances) != 1 || apps.Applications[0].Instances[0].InstanceId != "i-1" {
+		t.Fatalf("unexpected Instances: %+v", apps.Applications[0].Instances)
+	}
+}
+
+func TestQueryAllInstanceByAppId_UnwrapsRealEurekaEnvelope(t *testing.T) {
+	api, closeServer := serveJson(t, realApplicationPayload)
+	defer closeServer()
+
+	app, err := api.QueryAllInstanceByAppId("MY-SERVICE")
+	if err != nil {
+		t.Fatalf("QueryAllInstanceByAppId returned err=%s", err)
+	}
+
+	if app.Name != "MY-SERVICE" {
+		t.Fatalf("Name = %q, want %q", app.Name, "MY-SERVICE")
+	}
+	if len(app.Instances) != 1 || app.Instances[0].IpAddr != "10.0.0.1" {
+		t.Fatalf("unexpected Instances: %+v", app.Instances)
+	}
+}
+
+func TestQueryDelta_UnwrapsRealEurekaEnvelope(t *testing.T) {
+	api, closeServer := serveJson(t, realDeltaPayload)
+	defer closeServer()
+
+	delta, err := api.QueryDelta()
+	if err != nil {
+		t.Fatalf("QueryDelta returned err=%s", err)
+	}
+
+	if delta.AppsHashcode != "UP_1_" {
+		t.Fatalf("AppsHashcode = %q, want %q", delta.AppsHashcode, "UP_1_")
+	}
+	if len(delta.Applications) != 1 || len(delta.Applications[0].Instances) != 1 {
+		t.Fatalf("unexpected Applications: %+v", delta.Applications)
+	}
+	if delta.Applications[0].Instances[0].ActionType != ActionAdded {
+		t.Fatalf("ActionType = %q, want %q", delta.Applications[0].Instances[0].ActionType, ActionAdded)
+	}
+}
+
+func TestIsServerError(t *testing.T) {
+	if IsServerError(nil) {
+		t.Fatal("nil error should not be a server error")
+	}
+	if IsServerError(&HttpStatusError{StatusCode: 404}) {
+		t.Fatal("a 4xx HttpStatusError should not be a server error")
+	}
+	if !IsServerError(&HttpStatusError{StatusCode: 503}) {
+		t.Fatal("a 5xx HttpStatusError should be a server error")
+	}
+	if !IsServerError(http.ErrHandlerTimeout) {
+		t.Fatal("a non-HttpStatusError (transport failure) should be a server error")
+	}
+}
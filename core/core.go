@@ -0,0 +1,54 @@
+// Package core holds the data types exchanged with the eureka REST API.
+package core
+
+// Instance status values, as defined by the eureka wire protocol.
+const (
+	STATUS_UP             = "UP"
+	STATUS_DOWN           = "DOWN"
+	STATUS_STARTING       = "STARTING"
+	STATUS_OUT_OF_SERVICE = "OUT_OF_SERVICE"
+	STATUS_UNKNOWN        = "UNKNOWN"
+)
+
+// Port describes either the plain-http or secure-http port of an instance.
+type Port struct {
+	Port    int    `json:"$"`
+	Enabled string `json:"@enabled"`
+}
+
+// Instance is a single service instance as returned by the eureka registry.
+type Instance struct {
+	InstanceId string            `json:"instanceId"`
+	App        string            `json:"app"`
+	IpAddr     string            `json:"ipAddr"`
+	Status     string            `json:"status"`
+	Port       Port              `json:"port"`
+	SecurePort Port              `json:"securePort"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Application groups the instances registered under the same appId.
+type Application struct {
+	Name      string     `json:"name"`
+	Instances []Instance `json:"instance"`
+}
+
+// Applications is the payload returned by GET /apps.
+type Applications struct {
+	Applications []Application `json:"application"`
+	AppsHashcode string        `json:"apps__hashcode"`
+}
+
+// applicationsEnvelope is the actual top-level JSON eureka sends for
+// GET /apps: {"applications": {"application": [...], "apps__hashcode": "..."}}.
+// QueryAllInstances unmarshals into this and returns the inner Applications.
+type applicationsEnvelope struct {
+	Applications Applications `json:"applications"`
+}
+
+// applicationEnvelope is the actual top-level JSON eureka sends for
+// GET /apps/{appId}: {"application": {...}}. QueryAllInstanceByAppId
+// unmarshals into this and returns the inner Application.
+type applicationEnvelope struct {
+	Application Application `json:"application"`
+}
@@ -0,0 +1,48 @@
+package core
+
+import "net/http"
+
+// Delta action types, as reported by the eureka /apps/delta endpoint.
+const (
+	ActionAdded    = "ADDED"
+	ActionModified = "MODIFIED"
+	ActionDeleted  = "DELETED"
+)
+
+// DeltaInstance is an Instance plus the action eureka applied to it since the
+// last delta fetch.
+type DeltaInstance struct {
+	Instance
+	ActionType string `json:"actionType"`
+}
+
+// DeltaApplication groups delta instances by appId, same shape as Application.
+type DeltaApplication struct {
+	Name      string          `json:"name"`
+	Instances []DeltaInstance `json:"instance"`
+}
+
+// DeltaApplications is the payload returned by GET /apps/delta. AppsHashcode
+// is eureka's "STATUS_count_" hashcode (instance counts per status, e.g.
+// "DOWN_1_UP_3_") over the full registry and is used to detect whether applying this delta kept the local cache in sync.
+type DeltaApplications struct {
+	Applications []DeltaApplication `json:"application"`
+	AppsHashcode string             `json:"apps__hashcode"`
+}
+
+// deltaApplicationsEnvelope is the actual top-level JSON eureka sends for
+// GET /apps/delta: {"applications": {"application": [...], "apps__hashcode":
+// "..."}}, same wrapper shape as GET /apps. QueryDelta unmarshals into this
+// and returns the inner DeltaApplications.
+type deltaApplicationsEnvelope struct {
+	Applications DeltaApplications `json:"applications"`
+}
+
+// QueryDelta fetches the incremental changes since the last full/delta fetch.
+func (api *EurekaServerApi) QueryDelta() (*DeltaApplications, error) {
+	envelope := &deltaApplicationsEnvelope{}
+	if err := api.doRequest(http.MethodGet, "/apps/delta", nil, envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.Applications, nil
+}
@@ -0,0 +1,157 @@
+package eureka
+
+import (
+	"encoding/json"
+	"fmt"
+	core "github.com/phpdragon/go-eurake-client/core"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+)
+
+// statusResponse is the payload for GET /status.
+type statusResponse struct {
+	Status              string    `json:"status"`
+	LastHeartbeatAt     time.Time `json:"lastHeartbeatAt,omitempty"`
+	LastSuccessfulFetch time.Time `json:"lastSuccessfulFetch,omitempty"`
+}
+
+// updateStatusRequest is the payload for POST /instance/status.
+type updateStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// startGovernor starts the embedded admin HTTP server on
+// ClientConfig.GovernorAddr, if configured. It is stopped by Shutdown().
+func (client *Client) startGovernor() {
+	addr := client.config.ClientConfig.GovernorAddr
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", client.handleStatus)
+	mux.HandleFunc("/apps", client.handleApps)
+	mux.HandleFunc("/apps/", client.handleAppById)
+	mux.HandleFunc("/instance/status", client.handleUpdateInstanceStatus)
+	mux.HandleFunc("/deregister", client.handleDeregister)
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	client.governor = &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := client.governor.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			client.logger.Error(fmt.Sprintf("Governor server stopped, err=%s", err.Error()))
+		}
+	}()
+
+	client.logger.Info(fmt.Sprintf("Governor server listening on %s", addr))
+}
+
+// stopGovernor shuts down the embedded admin HTTP server, if running.
+func (client *Client) stopGovernor() {
+	if client.governor == nil {
+		return
+	}
+	_ = client.governor.Close()
+}
+
+func (client *Client) handleStatus(w http.ResponseWriter, r *http.Request) {
+	client.mutex.RLock()
+	resp := statusResponse{
+		Status:              client.instance.Status,
+		LastHeartbeatAt:     client.lastHeartbeatAt,
+		LastSuccessfulFetch: client.lastFetchAt,
+	}
+	client.mutex.RUnlock()
+
+	writeJson(w, http.StatusOK, resp)
+}
+
+func (client *Client) handleApps(w http.ResponseWriter, r *http.Request) {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	writeJson(w, http.StatusOK, client.registryAppMap)
+}
+
+func (client *Client) handleAppById(w http.ResponseWriter, r *http.Request) {
+	appId := strings.TrimPrefix(r.URL.Path, "/apps/")
+	if appId == "" {
+		client.handleApps(w, r)
+		return
+	}
+
+	client.mutex.RLock()
+	app, ok := client.registryAppMap[appId]
+	instances := client.activeInstanceMap[appId]
+	client.mutex.RUnlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJson(w, http.StatusOK, struct {
+		Application *core.Application      `json:"application"`
+		Instances   map[int]*core.Instance `json:"activeInstances"`
+	}{app, instances})
+}
+
+func (client *Client) handleUpdateInstanceStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req updateStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	api, err := client.Api()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if err := api.UpdateInstanceStatus(client.instance.App, client.instance.InstanceId, req.Status); err != nil {
+		if core.IsServerError(err) {
+			client.quarantineServerUrl(api.GetBaseUrl())
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	client.mutex.Lock()
+	client.instance.Status = req.Status
+	client.mutex.Unlock()
+
+	writeJson(w, http.StatusOK, map[string]string{"status": req.Status})
+}
+
+func (client *Client) handleDeregister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Write the response before triggering shutdown: Shutdown() closes the
+	// governor server immediately (stopGovernor -> http.Server.Close), which
+	// would otherwise race this handler's own response against the server
+	// tearing itself down and could reset the caller's connection instead of
+	// delivering the acknowledgement.
+	writeJson(w, http.StatusAccepted, map[string]string{"status": "deregistering"})
+	go client.Shutdown()
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
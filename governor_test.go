@@ -0,0 +1,82 @@
+package eureka
+
+import (
+	core "github.com/phpdragon/go-eurake-client/core"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newGovernorTestClient(t *testing.T) *Client {
+	t.Helper()
+	client := NewClient(&Config{})
+	client.registryAppMap = make(map[string]*core.Application)
+	client.activeInstanceMap = make(map[string]map[int]*core.Instance)
+	return client
+}
+
+func TestHandleStatus_ReturnsCurrentInstanceStatus(t *testing.T) {
+	client := newGovernorTestClient(t)
+	client.instance.Status = core.STATUS_UP
+
+	rr := httptest.NewRecorder()
+	client.handleStatus(rr, httptest.NewRequest("GET", "/status", nil))
+
+	if rr.Code != 200 {
+		t.Fatalf("status code = %d, want 200", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"status":"UP"`) {
+		t.Fatalf("body = %s, want it to report status UP", rr.Body.String())
+	}
+}
+
+func TestHandleAppById_NotFoundForUnknownApp(t *testing.T) {
+	client := newGovernorTestClient(t)
+
+	rr := httptest.NewRecorder()
+	client.handleAppById(rr, httptest.NewRequest("GET", "/apps/unknown", nil))
+
+	if rr.Code != 404 {
+		t.Fatalf("status code = %d, want 404", rr.Code)
+	}
+}
+
+func TestHandleAppById_ReturnsRegisteredApp(t *testing.T) {
+	client := newGovernorTestClient(t)
+	client.registryAppMap["APP1"] = &core.Application{Name: "APP1", Instances: []core.Instance{{InstanceId: "i1"}}}
+
+	rr := httptest.NewRecorder()
+	client.handleAppById(rr, httptest.NewRequest("GET", "/apps/APP1", nil))
+
+	if rr.Code != 200 {
+		t.Fatalf("status code = %d, want 200", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"i1"`) {
+		t.Fatalf("body = %s, want it to include instance i1", rr.Body.String())
+	}
+}
+
+func TestHandleDeregister_RejectsNonPost(t *testing.T) {
+	client := newGovernorTestClient(t)
+
+	rr := httptest.NewRecorder()
+	client.handleDeregister(rr, httptest.NewRequest("GET", "/deregister", nil))
+
+	if rr.Code != 405 {
+		t.Fatalf("status code = %d, want 405", rr.Code)
+	}
+}
+
+func TestHandleDeregister_WritesAcceptedResponseBeforeReturning(t *testing.T) {
+	client := newGovernorTestClient(t)
+
+	rr := httptest.NewRecorder()
+	client.handleDeregister(rr, httptest.NewRequest("POST", "/deregister", nil))
+
+	// The 202 response must already be recorded by the time the handler
+	// returns, i.e. written before Shutdown (backgrounded via go
+	// client.Shutdown()) gets a chance to close the governor server.
+	if rr.Code != 202 {
+		t.Fatalf("status code = %d, want 202", rr.Code)
+	}
+}
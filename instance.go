@@ -0,0 +1,32 @@
+package eureka
+
+import (
+	"fmt"
+	core "github.com/phpdragon/go-eurake-client/core"
+	netUtil "github.com/phpdragon/go-eurake-client/netutil"
+)
+
+// NewInstance builds the core.Instance that will be registered to eureka,
+// derived from the given config.
+func NewInstance(config *Config) (*core.Instance, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config can't be nil")
+	}
+
+	ip, err := netUtil.LocalIp()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local ip, err=%s", err.Error())
+	}
+
+	status := core.STATUS_STARTING
+	if config.InstanceConfig.InstanceEnabledOnInit {
+		status = core.STATUS_UP
+	}
+
+	instance := &core.Instance{
+		IpAddr: ip,
+		Status: status,
+	}
+
+	return instance, nil
+}
@@ -0,0 +1,122 @@
+package eureka
+
+import (
+	core "github.com/phpdragon/go-eurake-client/core"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// ewmaAlpha weights how much a new latency sample moves the running average.
+	ewmaAlpha = 0.2
+
+	// latencyEpsilon avoids a divide-by-zero/infinite weight for an instance
+	// with a zero EWMA (e.g. before any samples arrived).
+	latencyEpsilon = time.Millisecond
+
+	// errorCooldown is how long an instance that just errored is demoted for.
+	errorCooldown = 30 * time.Second
+
+	// demotedWeightFactor is the selection-weight multiplier applied to an
+	// instance that errored within errorCooldown.
+	demotedWeightFactor = 0.1
+)
+
+// latencyStats is the running EWMA + last-error bookkeeping for one instance.
+type latencyStats struct {
+	ewma      time.Duration
+	lastErrAt time.Time
+}
+
+// LatencyAware picks instances probabilistically, weighted by the inverse of
+// their recent EWMA latency, and demotes instances that errored recently.
+// Latency/error samples are reported via Client.ReportLatency.
+type LatencyAware struct {
+	mutex sync.RWMutex
+	stats map[string]*latencyStats
+
+	randMutex sync.Mutex
+	rand      *rand.Rand
+}
+
+// NewLatencyAware builds a LatencyAware load balancer.
+func NewLatencyAware() *LatencyAware {
+	return &LatencyAware{
+		stats: make(map[string]*latencyStats),
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (la *LatencyAware) Choose(appId string, instances []*core.Instance) (*core.Instance, error) {
+	if len(instances) == 0 {
+		return nil, noInstancesErr(appId)
+	}
+
+	weights := make([]float64, len(instances))
+	total := 0.0
+
+	la.mutex.RLock()
+	for i, instance := range instances {
+		weights[i] = la.weightLocked(instance.InstanceId)
+		total += weights[i]
+	}
+	la.mutex.RUnlock()
+
+	la.randMutex.Lock()
+	pick := la.rand.Float64() * total
+	la.randMutex.Unlock()
+	for i, weight := range weights {
+		pick -= weight
+		if pick <= 0 {
+			return instances[i], nil
+		}
+	}
+
+	return instances[len(instances)-1], nil
+}
+
+// weightLocked returns 1/(ewma+epsilon), demoted by demotedWeightFactor if
+// instanceId errored within errorCooldown. Callers must hold la.mutex (read
+// or write).
+func (la *LatencyAware) weightLocked(instanceId string) float64 {
+	stat, ok := la.stats[instanceId]
+	if !ok {
+		return 1.0 / latencyEpsilon.Seconds()
+	}
+
+	weight := 1.0 / (stat.ewma.Seconds() + latencyEpsilon.Seconds())
+	if !stat.lastErrAt.IsZero() && time.Since(stat.lastErrAt) < errorCooldown {
+		weight *= demotedWeightFactor
+	}
+	return weight
+}
+
+// report records a request's latency/error for instanceId, updating its
+// EWMA and cool-down window.
+func (la *LatencyAware) report(instanceId string, d time.Duration, err error) {
+	la.mutex.Lock()
+	defer la.mutex.Unlock()
+
+	stat, ok := la.stats[instanceId]
+	if !ok {
+		stat = &latencyStats{ewma: d}
+		la.stats[instanceId] = stat
+	} else {
+		stat.ewma = time.Duration(ewmaAlpha*float64(d) + (1-ewmaAlpha)*float64(stat.ewma))
+	}
+
+	if err != nil {
+		stat.lastErrAt = time.Now()
+	}
+}
+
+// ReportLatency feeds a completed request's latency/error back into the
+// client's LoadBalancer, if it supports it (currently only LatencyAware).
+// Callers should invoke this after using an instance returned by
+// GetNextServerFromEureka.
+func (client *Client) ReportLatency(instanceId string, d time.Duration, err error) {
+	if la, ok := client.config.LoadBalancer.(*LatencyAware); ok {
+		la.report(instanceId, d, err)
+	}
+}
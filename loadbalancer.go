@@ -0,0 +1,106 @@
+package eureka
+
+import (
+	"fmt"
+	core "github.com/phpdragon/go-eurake-client/core"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LoadBalancer chooses one instance to use for appId out of the currently
+// active instances. Implementations must be safe for concurrent use, since
+// Api() callers may invoke Choose from many goroutines.
+type LoadBalancer interface {
+	Choose(appId string, instances []*core.Instance) (*core.Instance, error)
+}
+
+func noInstancesErr(appId string) error {
+	return fmt.Errorf("This %s instances not exist!", appId)
+}
+
+// RoundRobin is the default LoadBalancer and matches the client's original
+// getRandIndex behavior: an ever-incrementing counter modulo the instance
+// count.
+type RoundRobin struct {
+	counter *int64Counter
+}
+
+// NewRoundRobin builds a RoundRobin load balancer.
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{counter: &int64Counter{}}
+}
+
+func (rr *RoundRobin) Choose(appId string, instances []*core.Instance) (*core.Instance, error) {
+	if len(instances) == 0 {
+		return nil, noInstancesErr(appId)
+	}
+	index := rr.counter.next() % int64(len(instances))
+	return instances[index], nil
+}
+
+// int64Counter is a tiny mutex-guarded counter, avoiding a dependency on
+// go.uber.org/atomic for a single new field.
+type int64Counter struct {
+	mutex sync.Mutex
+	value int64
+}
+
+func (c *int64Counter) next() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.value++
+	return c.value
+}
+
+// RandomWeighted picks an instance at random, weighted by its declared
+// Metadata["weight"] (parsed as a float, defaulting to 1 when absent or
+// invalid).
+type RandomWeighted struct {
+	rand *rand.Rand
+	mu   sync.Mutex
+}
+
+// NewRandomWeighted builds a RandomWeighted load balancer.
+func NewRandomWeighted() *RandomWeighted {
+	return &RandomWeighted{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (rw *RandomWeighted) Choose(appId string, instances []*core.Instance) (*core.Instance, error) {
+	if len(instances) == 0 {
+		return nil, noInstancesErr(appId)
+	}
+
+	weights := make([]float64, len(instances))
+	total := 0.0
+	for i, instance := range instances {
+		weights[i] = instanceWeight(instance)
+		total += weights[i]
+	}
+
+	rw.mu.Lock()
+	pick := rw.rand.Float64() * total
+	rw.mu.Unlock()
+
+	for i, weight := range weights {
+		pick -= weight
+		if pick <= 0 {
+			return instances[i], nil
+		}
+	}
+
+	return instances[len(instances)-1], nil
+}
+
+func instanceWeight(instance *core.Instance) float64 {
+	raw, ok := instance.Metadata["weight"]
+	if !ok {
+		return 1
+	}
+	weight, err := strconv.ParseFloat(raw, 64)
+	if err != nil || weight <= 0 {
+		return 1
+	}
+	return weight
+}
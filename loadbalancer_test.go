@@ -0,0 +1,99 @@
+package eureka
+
+import (
+	core "github.com/phpdragon/go-eurake-client/core"
+	"sync"
+	"testing"
+	"time"
+)
+
+func instancesFor(ids ...string) []*core.Instance {
+	instances := make([]*core.Instance, len(ids))
+	for i, id := range ids {
+		instances[i] = &core.Instance{InstanceId: id}
+	}
+	return instances
+}
+
+func TestRoundRobin_ChooseCyclesThroughInstances(t *testing.T) {
+	rr := NewRoundRobin()
+	instances := instancesFor("a", "b", "c")
+
+	seen := make([]string, 6)
+	for i := range seen {
+		instance, err := rr.Choose("app", instances)
+		if err != nil {
+			t.Fatalf("Choose returned err=%s", err)
+		}
+		seen[i] = instance.InstanceId
+	}
+
+	want := []string{"b", "c", "a", "b", "c", "a"}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("pick[%d]=%s, want %s (full sequence=%v)", i, seen[i], want[i], seen)
+		}
+	}
+}
+
+func TestRoundRobin_NoInstancesReturnsError(t *testing.T) {
+	rr := NewRoundRobin()
+	if _, err := rr.Choose("app", nil); err == nil {
+		t.Fatal("expected error for empty instance list, got nil")
+	}
+}
+
+func TestRandomWeighted_ConcurrentChooseIsRaceFree(t *testing.T) {
+	rw := NewRandomWeighted()
+	instances := instancesFor("a", "b", "c")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := rw.Choose("app", instances); err != nil {
+				t.Errorf("Choose returned err=%s", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLatencyAware_ConcurrentChooseAndReportIsRaceFree(t *testing.T) {
+	la := NewLatencyAware()
+	instances := instancesFor("a", "b", "c")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			instance, err := la.Choose("app", instances)
+			if err != nil {
+				t.Errorf("Choose returned err=%s", err)
+				return
+			}
+			la.report(instance.InstanceId, time.Millisecond, nil)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestLatencyAware_DemotesRecentlyErroredInstance(t *testing.T) {
+	la := NewLatencyAware()
+	la.report("slow", 10*time.Millisecond, errExpected)
+
+	healthyWeight := la.weightLocked("healthy")
+	demotedWeight := la.weightLocked("slow")
+
+	if demotedWeight >= healthyWeight {
+		t.Fatalf("demoted weight %v should be lower than healthy weight %v", demotedWeight, healthyWeight)
+	}
+}
+
+var errExpected = &testError{"simulated failure"}
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }
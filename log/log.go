@@ -0,0 +1,33 @@
+// Package log wraps zap so callers don't have to deal with a nil *zap.Logger.
+package log
+
+import "go.uber.org/zap"
+
+// ClientLogger is a small convenience wrapper around *zap.Logger.
+type ClientLogger struct {
+	logger *zap.Logger
+}
+
+// NewLogAgent builds a ClientLogger, falling back to zap.NewProduction() when zapLog is nil.
+func NewLogAgent(zapLog *zap.Logger) *ClientLogger {
+	if zapLog == nil {
+		zapLog, _ = zap.NewProduction()
+	}
+	return &ClientLogger{logger: zapLog}
+}
+
+func (l *ClientLogger) Info(msg string) {
+	l.logger.Info(msg)
+}
+
+func (l *ClientLogger) Warn(msg string) {
+	l.logger.Warn(msg)
+}
+
+func (l *ClientLogger) Error(msg string) {
+	l.logger.Error(msg)
+}
+
+func (l *ClientLogger) Debug(msg string) {
+	l.logger.Debug(msg)
+}
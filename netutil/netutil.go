@@ -0,0 +1,36 @@
+// Package netutil provides small local-network helpers shared by the client.
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// PortInUse reports whether something is listening on host:port.
+func PortInUse(host string, port int) bool {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", host, port), time.Second)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	return true
+}
+
+// LocalIp returns the non-loopback IPv4 address of this host.
+func LocalIp() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
+			if ip4 := ipNet.IP.To4(); ip4 != nil {
+				return ip4.String(), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no non-loopback ipv4 address found")
+}
@@ -0,0 +1,127 @@
+package eureka
+
+import (
+	"fmt"
+	core "github.com/phpdragon/go-eurake-client/core"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// serverList tracks the shuffled, zone-ordered eureka server URLs for one
+// client instance, plus a quarantine set for URLs that recently failed.
+type serverList struct {
+	mutex sync.RWMutex
+
+	urls []string
+
+	quarantine map[string]time.Time
+
+	builtAt time.Time
+	rand    *rand.Rand
+}
+
+func newServerList() *serverList {
+	return &serverList{
+		quarantine: make(map[string]time.Time),
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// pick returns the first non-quarantined URL, rebuilding/reshuffling the
+// list first if it's empty or stale.
+func (sl *serverList) pick(serviceURL ServiceURL) (string, error) {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+
+	refreshInterval := time.Duration(serviceURL.getServerListRefreshIntervalMs()) * time.Millisecond
+	if len(sl.urls) == 0 || time.Since(sl.builtAt) >= refreshInterval {
+		sl.urls = sl.buildShuffledUrls(serviceURL)
+		sl.quarantine = make(map[string]time.Time)
+		sl.builtAt = time.Now()
+	}
+
+	for _, url := range sl.urls {
+		if quarantinedAt, ok := sl.quarantine[url]; ok {
+			if time.Since(quarantinedAt) < refreshInterval {
+				continue
+			}
+			delete(sl.quarantine, url)
+		}
+		return url, nil
+	}
+
+	return "", fmt.Errorf("all eureka server urls are quarantined")
+}
+
+// quarantineUrl marks url as unusable until the next list rebuild.
+func (sl *serverList) quarantineUrl(url string) {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	sl.quarantine[url] = time.Now()
+}
+
+// buildShuffledUrls shuffles each zone's URLs and puts the preferred zone's
+// URLs first, followed by every other zone. DefaultZone's URLs are used only
+// when Zones yields none.
+func (sl *serverList) buildShuffledUrls(serviceURL ServiceURL) []string {
+	var ordered []string
+
+	if preferred, ok := serviceURL.Zones[serviceURL.PreferredZone]; ok {
+		ordered = append(ordered, sl.shuffle(splitUrls(preferred))...)
+	}
+
+	for zone, urls := range serviceURL.Zones {
+		if zone == serviceURL.PreferredZone {
+			continue
+		}
+		ordered = append(ordered, sl.shuffle(splitUrls(urls))...)
+	}
+
+	if len(ordered) == 0 {
+		ordered = sl.shuffle(splitUrls(serviceURL.DefaultZone))
+	}
+
+	return ordered
+}
+
+func (sl *serverList) shuffle(urls []string) []string {
+	shuffled := make([]string, len(urls))
+	copy(shuffled, urls)
+	sl.rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+func splitUrls(raw string) []string {
+	var urls []string
+	for _, url := range strings.Split(raw, ",") {
+		url = strings.TrimSpace(url)
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// pickEurekaServerApi picks the next usable eureka server URL, shuffled per
+// zone with the preferred zone first, and builds an EurekaServerApi for it.
+// On transport/5xx failure callers should call client.quarantineServerUrl so
+// subsequent picks skip it until ServerListRefreshIntervalMs elapses.
+func (client *Client) pickEurekaServerApi() (*core.EurekaServerApi, error) {
+	url, err := client.serverList.pick(client.config.ServiceURL)
+	if err != nil {
+		client.logger.Error(fmt.Sprintf("Failed to pick eureka server url, err=%s", err.Error()))
+		return nil, err
+	}
+
+	return core.NewEurekaServerApi(url), nil
+}
+
+// quarantineServerUrl pushes url into the quarantine set and logs the event.
+func (client *Client) quarantineServerUrl(url string) {
+	client.serverList.quarantineUrl(url)
+	client.logger.Warn(fmt.Sprintf("Quarantined eureka server url=%s", url))
+}
@@ -0,0 +1,83 @@
+package eureka
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServerList_PreferredZoneUrlsComeFirst(t *testing.T) {
+	sl := newServerList()
+	serviceURL := ServiceURL{
+		Zones: map[string]string{
+			"zone-a": "http://a1:8761/eureka/",
+			"zone-b": "http://b1:8761/eureka/",
+		},
+		PreferredZone: "zone-b",
+	}
+
+	url, err := sl.pick(serviceURL)
+	if err != nil {
+		t.Fatalf("pick returned err=%s", err)
+	}
+	if url != "http://b1:8761/eureka/" {
+		t.Fatalf("pick = %q, want the preferred zone's url", url)
+	}
+}
+
+func TestServerList_FallsBackToDefaultZoneWhenNoZonesConfigured(t *testing.T) {
+	sl := newServerList()
+	serviceURL := ServiceURL{DefaultZone: "http://d1:8761/eureka/,http://d2:8761/eureka/"}
+
+	url, err := sl.pick(serviceURL)
+	if err != nil {
+		t.Fatalf("pick returned err=%s", err)
+	}
+	if url != "http://d1:8761/eureka/" && url != "http://d2:8761/eureka/" {
+		t.Fatalf("pick = %q, want one of the default zone urls", url)
+	}
+}
+
+func TestServerList_QuarantinedUrlIsSkippedUntilItExpires(t *testing.T) {
+	sl := newServerList()
+	serviceURL := ServiceURL{
+		DefaultZone:                 "http://only:8761/eureka/",
+		ServerListRefreshIntervalMs: 50,
+	}
+
+	url, err := sl.pick(serviceURL)
+	if err != nil {
+		t.Fatalf("pick returned err=%s", err)
+	}
+	sl.quarantineUrl(url)
+
+	if _, err := sl.pick(serviceURL); err == nil {
+		t.Fatal("expected pick to fail while the only url is quarantined")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if _, err := sl.pick(serviceURL); err != nil {
+		t.Fatalf("expected quarantine to have expired, got err=%s", err)
+	}
+}
+
+func TestServerList_RebuildsAfterRefreshIntervalElapses(t *testing.T) {
+	sl := newServerList()
+	serviceURL := ServiceURL{
+		DefaultZone:                 "http://only:8761/eureka/",
+		ServerListRefreshIntervalMs: 50,
+	}
+
+	if _, err := sl.pick(serviceURL); err != nil {
+		t.Fatalf("pick returned err=%s", err)
+	}
+	sl.quarantineUrl("http://only:8761/eureka/")
+
+	time.Sleep(60 * time.Millisecond)
+
+	// The refresh interval has elapsed, so the rebuild at the top of pick
+	// clears the quarantine set and this should succeed.
+	if _, err := sl.pick(serviceURL); err != nil {
+		t.Fatalf("expected list rebuild to clear quarantine, got err=%s", err)
+	}
+}
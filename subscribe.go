@@ -0,0 +1,165 @@
+package eureka
+
+import (
+	"fmt"
+	core "github.com/phpdragon/go-eurake-client/core"
+	"strings"
+)
+
+// subscriberBufferSize bounds each subscriber channel. When full, the oldest
+// pending snapshot is dropped in favor of the newest one.
+const subscriberBufferSize = 4
+
+// CancelFunc unsubscribes a previously-registered channel and closes it.
+type CancelFunc func()
+
+// RegistryEventType describes what changed about an application's instances.
+type RegistryEventType string
+
+const (
+	// RegistryEventChanged fires whenever an app's instance set differs from
+	// the previous snapshot (membership or status).
+	RegistryEventChanged RegistryEventType = "CHANGED"
+)
+
+// RegistryEvent is delivered to SubscribeAll subscribers whenever any app's
+// instance set changes.
+type RegistryEvent struct {
+	Type      RegistryEventType
+	AppId     string
+	Instances []*core.Instance
+}
+
+// Subscribe returns a channel that receives a snapshot of appId's instances
+// every time the set changes (membership or status), plus a CancelFunc to
+// stop receiving and release the channel.
+func (client *Client) Subscribe(appId string) (<-chan []*core.Instance, CancelFunc) {
+	id := strings.ToUpper(appId)
+	ch := make(chan []*core.Instance, subscriberBufferSize)
+
+	client.mutex.Lock()
+	if client.appSubscribers == nil {
+		client.appSubscribers = make(map[string][]chan []*core.Instance)
+	}
+	client.appSubscribers[id] = append(client.appSubscribers[id], ch)
+	client.mutex.Unlock()
+
+	return ch, func() { client.cancelAppSubscription(id, ch) }
+}
+
+// SubscribeAll returns a channel that receives a RegistryEvent whenever any
+// app's instance set changes, plus a CancelFunc to stop receiving.
+func (client *Client) SubscribeAll() (<-chan RegistryEvent, CancelFunc) {
+	ch := make(chan RegistryEvent, subscriberBufferSize)
+
+	client.mutex.Lock()
+	client.allSubscribers = append(client.allSubscribers, ch)
+	client.mutex.Unlock()
+
+	return ch, func() { client.cancelAllSubscription(ch) }
+}
+
+func (client *Client) cancelAppSubscription(appId string, ch chan []*core.Instance) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	subscribers := client.appSubscribers[appId]
+	for i, subscriber := range subscribers {
+		if subscriber == ch {
+			client.appSubscribers[appId] = append(subscribers[:i], subscribers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+func (client *Client) cancelAllSubscription(ch chan RegistryEvent) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	for i, subscriber := range client.allSubscribers {
+		if subscriber == ch {
+			client.allSubscribers = append(client.allSubscribers[:i], client.allSubscribers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+// notifySubscribersLocked compares previous vs. current instances for appId
+// and, if anything changed (by InstanceId + Status), fans out a snapshot to
+// every live subscriber for appId plus every SubscribeAll subscriber.
+// Callers must hold client.mutex.
+func (client *Client) notifySubscribersLocked(appId string, previous, current []*core.Instance) {
+	if instanceSetsEqual(previous, current) {
+		return
+	}
+
+	snapshot := make([]*core.Instance, len(current))
+	copy(snapshot, current)
+
+	for _, ch := range client.appSubscribers[appId] {
+		client.sendInstancesNonBlocking(appId, ch, snapshot)
+	}
+
+	event := RegistryEvent{Type: RegistryEventChanged, AppId: appId, Instances: snapshot}
+	for _, ch := range client.allSubscribers {
+		client.sendEventNonBlocking(ch, event)
+	}
+}
+
+func (client *Client) sendInstancesNonBlocking(appId string, ch chan []*core.Instance, snapshot []*core.Instance) {
+	select {
+	case ch <- snapshot:
+	default:
+		select {
+		case <-ch:
+			client.logger.Warn(fmt.Sprintf("Subscriber channel for app=%s is full, dropped oldest snapshot", appId))
+		default:
+		}
+		select {
+		case ch <- snapshot:
+		default:
+			client.logger.Warn(fmt.Sprintf("Subscriber channel for app=%s is full, dropped newest snapshot", appId))
+		}
+	}
+}
+
+func (client *Client) sendEventNonBlocking(ch chan RegistryEvent, event RegistryEvent) {
+	select {
+	case ch <- event:
+	default:
+		select {
+		case <-ch:
+			client.logger.Warn(fmt.Sprintf("SubscribeAll channel is full, dropped oldest event for app=%s", event.AppId))
+		default:
+		}
+		select {
+		case ch <- event:
+		default:
+			client.logger.Warn(fmt.Sprintf("SubscribeAll channel is full, dropped newest event for app=%s", event.AppId))
+		}
+	}
+}
+
+// instanceSetsEqual compares two instance slices by InstanceId + Status,
+// order-independent.
+func instanceSetsEqual(a, b []*core.Instance) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	statusById := make(map[string]string, len(a))
+	for _, instance := range a {
+		statusById[instance.InstanceId] = instance.Status
+	}
+
+	for _, instance := range b {
+		status, ok := statusById[instance.InstanceId]
+		if !ok || status != instance.Status {
+			return false
+		}
+	}
+
+	return true
+}
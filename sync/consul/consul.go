@@ -0,0 +1,104 @@
+// Package consul is an example sync.Syncer backend that registers eureka
+// instances into Consul's catalog via the agent HTTP API.
+package consul
+
+import (
+	"bytes"
+	"encoding/json"
+	core "github.com/phpdragon/go-eurake-client/core"
+	syncpkg "github.com/phpdragon/go-eurake-client/sync"
+	"net/http"
+)
+
+// Syncer pushes eureka instances into a Consul agent's local catalog.
+type Syncer struct {
+	agentAddr  string
+	httpClient *http.Client
+}
+
+// NewSyncer builds a Syncer talking to the Consul agent at agentAddr, e.g.
+// "http://127.0.0.1:8500".
+func NewSyncer(agentAddr string) *Syncer {
+	return &Syncer{agentAddr: agentAddr, httpClient: &http.Client{}}
+}
+
+type registerRequest struct {
+	ID      string   `json:"ID"`
+	Name    string   `json:"Name"`
+	Address string   `json:"Address"`
+	Port    int      `json:"Port"`
+	Tags    []string `json:"Tags"`
+	Check   *check   `json:"Check,omitempty"`
+}
+
+type check struct {
+	Status string `json:"Status"`
+	TTL    string `json:"TTL"`
+}
+
+// Upsert registers every instance of app with Consul's agent.
+func (s *Syncer) Upsert(app *core.Application) error {
+	for _, instance := range app.Instances {
+		target := syncpkg.ToTargetRegistration(app.Name, instance)
+
+		status := "passing"
+		if !target.HealthPassing {
+			status = "critical"
+		}
+
+		req := registerRequest{
+			ID:      target.ServiceId,
+			Name:    target.ServiceName,
+			Address: target.Address,
+			Port:    target.Port,
+			Tags:    target.Tags,
+			Check:   &check{Status: status, TTL: "30s"},
+		}
+
+		if err := s.put("/v1/agent/service/register", req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete de-registers instanceId from Consul's agent.
+func (s *Syncer) Delete(appId, instanceId string) error {
+	return s.put("/v1/agent/service/deregister/"+instanceId, nil)
+}
+
+func (s *Syncer) put(path string, body interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.agentAddr+path, &buf)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return &httpStatusError{path: path, statusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+type httpStatusError struct {
+	path       string
+	statusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return "consul agent returned status " + http.StatusText(e.statusCode) + " for " + e.path
+}
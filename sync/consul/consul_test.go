@@ -0,0 +1,82 @@
+package consul
+
+import (
+	"encoding/json"
+	core "github.com/phpdragon/go-eurake-client/core"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSyncer_UpsertRegistersEveryInstance(t *testing.T) {
+	var requests []*http.Request
+	var bodies []registerRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		var body registerRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s := NewSyncer(server.URL)
+	app := &core.Application{
+		Name: "APP1",
+		Instances: []core.Instance{
+			{InstanceId: "i1", IpAddr: "10.0.0.1", Status: core.STATUS_UP, Port: core.Port{Port: 8080}},
+			{InstanceId: "i2", IpAddr: "10.0.0.2", Status: core.STATUS_DOWN, Port: core.Port{Port: 8081}},
+		},
+	}
+
+	if err := s.Upsert(app); err != nil {
+		t.Fatalf("Upsert returned err=%s", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 register requests, got %d", len(requests))
+	}
+	for _, r := range requests {
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/agent/service/register" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}
+
+	if bodies[0].ID != "i1" || bodies[0].Check.Status != "passing" {
+		t.Fatalf("unexpected body for UP instance: %+v", bodies[0])
+	}
+	if bodies[1].ID != "i2" || bodies[1].Check.Status != "critical" {
+		t.Fatalf("unexpected body for DOWN instance: %+v", bodies[1])
+	}
+}
+
+func TestSyncer_DeleteDeregistersInstance(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s := NewSyncer(server.URL)
+	if err := s.Delete("APP1", "i1"); err != nil {
+		t.Fatalf("Delete returned err=%s", err)
+	}
+
+	if gotPath != "/v1/agent/service/deregister/i1" {
+		t.Fatalf("path = %q, want the deregister endpoint for i1", gotPath)
+	}
+}
+
+func TestSyncer_NonSuccessStatusReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s := NewSyncer(server.URL)
+	if err := s.Delete("APP1", "i1"); err == nil {
+		t.Fatal("expected an error for a 500 response")
+	}
+}
@@ -0,0 +1,205 @@
+// Package sync mirrors eureka registry changes into another discovery
+// backend (Consul, etcd, Nacos, k8s Services, ...) via a pluggable Syncer.
+package sync
+
+import (
+	"fmt"
+	core "github.com/phpdragon/go-eurake-client/core"
+	log "github.com/phpdragon/go-eurake-client/log"
+	"time"
+)
+
+const (
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
+// Syncer pushes eureka registry changes to another discovery backend. Third
+// parties implement this (Nacos, ServiceComb, k8s Services, ...) without
+// forking this package.
+type Syncer interface {
+	Upsert(app *core.Application) error
+	Delete(appId, instanceId string) error
+}
+
+// TargetRegistration is the backend-agnostic shape every Syncer adapter
+// translates a core.Instance into.
+type TargetRegistration struct {
+	ServiceId     string
+	ServiceName   string
+	Address       string
+	Port          int
+	Tags          []string
+	HealthPassing bool
+}
+
+// ToTargetRegistration is the public transform adapters reuse: it maps
+// InstanceId->ServiceId, App->ServiceName, IpAddr/Port->Address/Port,
+// Metadata->Tags and Status->HealthPassing.
+func ToTargetRegistration(appId string, instance core.Instance) TargetRegistration {
+	tags := make([]string, 0, len(instance.Metadata))
+	for key, value := range instance.Metadata {
+		tags = append(tags, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return TargetRegistration{
+		ServiceId:     instance.InstanceId,
+		ServiceName:   appId,
+		Address:       instance.IpAddr,
+		Port:          instance.Port.Port,
+		Tags:          tags,
+		HealthPassing: instance.Status == core.STATUS_UP,
+	}
+}
+
+// AppChange is one app's updated instance set, as produced by the eureka
+// delta/subscription stream.
+type AppChange struct {
+	AppId     string
+	Instances []core.Instance
+}
+
+// Snapshot is the minimal read-only view of the registry RegistrySync needs
+// to replay on start/reconnect, kept small to avoid importing the eureka
+// package (which imports this one to launch the driver).
+type Snapshot interface {
+	GetApplications() map[string]*core.Application
+}
+
+// RegistrySync consumes a stream of per-app AppChanges (e.g. adapted from
+// Client.SubscribeAll) and pushes translated records to every configured
+// Syncer, retrying with exponential backoff on target failures and replaying
+// the current snapshot on start and whenever a target accepts an Upsert again
+// after failing.
+type RegistrySync struct {
+	syncers  []Syncer
+	snapshot Snapshot
+	logger   *log.ClientLogger
+	stopChan chan struct{}
+
+	// lastInstanceIds is the per-appId instance id set from the previous
+	// apply, used to detect which instances dropped out so Delete gets
+	// called for them. Only ever touched from the Run goroutine.
+	lastInstanceIds map[string]map[string]struct{}
+}
+
+// NewRegistrySync builds a driver pushing to every given syncer.
+func NewRegistrySync(snapshot Snapshot, logger *log.ClientLogger, syncers ...Syncer) *RegistrySync {
+	return &RegistrySync{
+		syncers:         syncers,
+		snapshot:        snapshot,
+		logger:          logger,
+		stopChan:        make(chan struct{}),
+		lastInstanceIds: make(map[string]map[string]struct{}),
+	}
+}
+
+// Run replays the current snapshot, then applies every subsequent change
+// delivered on changes until Stop is called or changes is closed. Intended
+// to be run in its own goroutine.
+func (rs *RegistrySync) Run(changes <-chan AppChange) {
+	rs.replaySnapshot()
+
+	for {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			rs.apply(change)
+		case <-rs.stopChan:
+			return
+		}
+	}
+}
+
+// Stop terminates a running Run loop.
+func (rs *RegistrySync) Stop() {
+	close(rs.stopChan)
+}
+
+func (rs *RegistrySync) replaySnapshot() {
+	for appId, app := range rs.snapshot.GetApplications() {
+		rs.apply(AppChange{AppId: appId, Instances: app.Instances})
+	}
+}
+
+func (rs *RegistrySync) apply(change AppChange) {
+	app := &core.Application{Name: change.AppId, Instances: change.Instances}
+	for _, syncer := range rs.syncers {
+		rs.upsertWithBackoff(syncer, app)
+	}
+
+	currentIds := make(map[string]struct{}, len(change.Instances))
+	for _, instance := range change.Instances {
+		currentIds[instance.InstanceId] = struct{}{}
+	}
+
+	for instanceId := range rs.lastInstanceIds[change.AppId] {
+		if _, ok := currentIds[instanceId]; ok {
+			continue
+		}
+		for _, syncer := range rs.syncers {
+			rs.deleteWithBackoff(syncer, change.AppId, instanceId)
+		}
+	}
+
+	rs.lastInstanceIds[change.AppId] = currentIds
+}
+
+// upsertWithBackoff retries Upsert with exponential backoff until it
+// succeeds or Stop is called, so a target outage doesn't drop the update.
+// When the target recovers after a failure, the current snapshot is
+// replayed to it.
+func (rs *RegistrySync) upsertWithBackoff(syncer Syncer, app *core.Application) {
+	backoff := initialBackoff
+	failed := false
+	for {
+		err := syncer.Upsert(app)
+		if err == nil {
+			if failed {
+				for _, snapshotApp := range rs.snapshot.GetApplications() {
+					rs.upsertWithBackoff(syncer, snapshotApp)
+				}
+			}
+			return
+		}
+		failed = true
+		rs.logger.Error(fmt.Sprintf("sync target failed for app=%s, err=%s, retrying in %s", app.Name, err.Error(), backoff))
+
+		select {
+		case <-time.After(backoff):
+		case <-rs.stopChan:
+			return
+		}
+
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+// deleteWithBackoff retries Delete with exponential backoff until it
+// succeeds or Stop is called, mirroring upsertWithBackoff, so an instance
+// that dropped out of the registry during a target outage still gets
+// removed once the target recovers.
+func (rs *RegistrySync) deleteWithBackoff(syncer Syncer, appId, instanceId string) {
+	backoff := initialBackoff
+	for {
+		err := syncer.Delete(appId, instanceId)
+		if err == nil {
+			return
+		}
+		rs.logger.Error(fmt.Sprintf("sync target delete failed for app=%s, instance=%s, err=%s, retrying in %s", appId, instanceId, err.Error(), backoff))
+
+		select {
+		case <-time.After(backoff):
+		case <-rs.stopChan:
+			return
+		}
+
+		if backoff < maxBackoff {
+			backoff *= 2
+		}
+	}
+}
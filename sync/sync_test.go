@@ -0,0 +1,89 @@
+package sync
+
+import (
+	core "github.com/phpdragon/go-eurake-client/core"
+	log "github.com/phpdragon/go-eurake-client/log"
+	"sync"
+	"testing"
+)
+
+// fakeSyncer records every Upsert/Delete call it receives.
+type fakeSyncer struct {
+	mu      sync.Mutex
+	upserts []string
+	deletes []string
+}
+
+func (f *fakeSyncer) Upsert(app *core.Application) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.upserts = append(f.upserts, app.Name)
+	return nil
+}
+
+func (f *fakeSyncer) Delete(appId, instanceId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deletes = append(f.deletes, appId+"/"+instanceId)
+	return nil
+}
+
+type fakeSnapshot struct {
+	apps map[string]*core.Application
+}
+
+func (s *fakeSnapshot) GetApplications() map[string]*core.Application {
+	return s.apps
+}
+
+func newTestRegistrySync(syncer Syncer) *RegistrySync {
+	return NewRegistrySync(&fakeSnapshot{apps: map[string]*core.Application{}}, log.NewLogAgent(nil), syncer)
+}
+
+func TestRegistrySync_ApplyDeletesInstancesThatDropOut(t *testing.T) {
+	syncer := &fakeSyncer{}
+	rs := newTestRegistrySync(syncer)
+
+	rs.apply(AppChange{AppId: "APP1", Instances: []core.Instance{{InstanceId: "i1"}, {InstanceId: "i2"}}})
+	if len(syncer.deletes) != 0 {
+		t.Fatalf("expected no deletes on first apply, got %v", syncer.deletes)
+	}
+
+	rs.apply(AppChange{AppId: "APP1", Instances: []core.Instance{{InstanceId: "i1"}}})
+	if len(syncer.deletes) != 1 || syncer.deletes[0] != "APP1/i2" {
+		t.Fatalf("expected a delete for the dropped instance i2, got %v", syncer.deletes)
+	}
+}
+
+func TestRegistrySync_ApplyDoesNotDeleteInstancesStillPresent(t *testing.T) {
+	syncer := &fakeSyncer{}
+	rs := newTestRegistrySync(syncer)
+
+	rs.apply(AppChange{AppId: "APP1", Instances: []core.Instance{{InstanceId: "i1"}, {InstanceId: "i2"}}})
+	rs.apply(AppChange{AppId: "APP1", Instances: []core.Instance{{InstanceId: "i1"}, {InstanceId: "i2"}}})
+
+	if len(syncer.deletes) != 0 {
+		t.Fatalf("expected no deletes when membership is unchanged, got %v", syncer.deletes)
+	}
+	if len(syncer.upserts) != 2 {
+		t.Fatalf("expected an upsert per apply, got %d", len(syncer.upserts))
+	}
+}
+
+func TestRegistrySync_ReplaySnapshotEstablishesBaselineWithoutDeleting(t *testing.T) {
+	syncer := &fakeSyncer{}
+	snapshot := &fakeSnapshot{apps: map[string]*core.Application{
+		"APP1": {Name: "APP1", Instances: []core.Instance{{InstanceId: "i1"}}},
+	}}
+	rs := NewRegistrySync(snapshot, log.NewLogAgent(nil), syncer)
+
+	rs.replaySnapshot()
+	if len(syncer.deletes) != 0 {
+		t.Fatalf("replaySnapshot should never delete, got %v", syncer.deletes)
+	}
+
+	rs.apply(AppChange{AppId: "APP1", Instances: nil})
+	if len(syncer.deletes) != 1 || syncer.deletes[0] != "APP1/i1" {
+		t.Fatalf("expected i1 to be deleted once it drops out after the replayed baseline, got %v", syncer.deletes)
+	}
+}
@@ -0,0 +1,41 @@
+package eureka
+
+import (
+	core "github.com/phpdragon/go-eurake-client/core"
+	registrysync "github.com/phpdragon/go-eurake-client/sync"
+)
+
+// startSync launches a registrysync.RegistrySync mirroring this client's
+// registry into every configured ClientConfig.SyncTargets backend. It's a
+// no-op when no targets are configured.
+func (client *Client) startSync() {
+	if len(client.config.ClientConfig.SyncTargets) == 0 {
+		return
+	}
+
+	events, cancel := client.SubscribeAll()
+
+	sync := registrysync.NewRegistrySync(client, client.logger, client.config.ClientConfig.SyncTargets...)
+	client.registrySyncCancel = func() {
+		cancel()
+		sync.Stop()
+	}
+
+	changes := make(chan registrysync.AppChange)
+	go func() {
+		defer close(changes)
+		for event := range events {
+			changes <- registrysync.AppChange{AppId: event.AppId, Instances: toPlainInstances(event.Instances)}
+		}
+	}()
+
+	go sync.Run(changes)
+}
+
+func toPlainInstances(instances []*core.Instance) []core.Instance {
+	plain := make([]core.Instance, len(instances))
+	for i, instance := range instances {
+		plain[i] = *instance
+	}
+	return plain
+}